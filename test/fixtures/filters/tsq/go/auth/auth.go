@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/gbrlsnchs/jwt/v3"
+)
+
+// DefaultTTL is used when Issue is called with ttl == 0.
+const DefaultTTL = 30 * 24 * time.Hour
+
+// Claims carries just enough to authorize a request without a
+// round-trip to the user store.
+type Claims struct {
+	jwt.Payload
+	UserID string `json:"uid"`
+	Role   string `json:"role"`
+}
+
+type Manager struct {
+	algorithm *jwt.HMACSHA
+}
+
+func NewManager(secret []byte) *Manager {
+	return &Manager{algorithm: jwt.NewHS256(secret)}
+}
+
+// Issue falls back to DefaultTTL when ttl is 0.
+func (m *Manager) Issue(userID, role string, ttl time.Duration) (string, error) {
+	if ttl == 0 {
+		ttl = DefaultTTL
+	}
+	now := time.Now()
+	claims := Claims{
+		Payload: jwt.Payload{
+			IssuedAt:       jwt.NumericDate(now),
+			ExpirationTime: jwt.NumericDate(now.Add(ttl)),
+		},
+		UserID: userID,
+		Role:   role,
+	}
+	token, err := jwt.Sign(claims, m.algorithm)
+	if err != nil {
+		return "", err
+	}
+	return string(token), nil
+}
+
+func (m *Manager) Verify(token string) (*Claims, error) {
+	var claims Claims
+	validatePayload := jwt.ValidatePayload(&claims.Payload, jwt.ExpirationTimeValidator(time.Now()))
+	if _, err := jwt.Verify([]byte(token), m.algorithm, &claims, validatePayload); err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestManager_IssueVerifyRoundTrip(t *testing.T) {
+	m := NewManager([]byte("secret"))
+
+	token, err := m.Issue("u1", "admin", time.Hour)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	claims, err := m.Verify(token)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if claims.UserID != "u1" || claims.Role != "admin" {
+		t.Fatalf("Verify claims = %+v, want UserID=u1 Role=admin", claims)
+	}
+}
+
+func TestManager_IssueZeroTTLFallsBackToDefault(t *testing.T) {
+	m := NewManager([]byte("secret"))
+
+	token, err := m.Issue("u1", "user", 0)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	claims, err := m.Verify(token)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	want := claims.IssuedAt.Time.Add(DefaultTTL)
+	got := claims.ExpirationTime.Time
+	if !got.Equal(want) {
+		t.Fatalf("ExpirationTime = %v, want %v", got, want)
+	}
+}
+
+func TestManager_VerifyRejectsExpiredToken(t *testing.T) {
+	m := NewManager([]byte("secret"))
+
+	token, err := m.Issue("u1", "user", -time.Hour)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if _, err := m.Verify(token); err == nil {
+		t.Fatal("Verify on expired token: got nil error, want error")
+	}
+}
+
+func TestManager_VerifyRejectsWrongSecret(t *testing.T) {
+	token, err := NewManager([]byte("secret")).Issue("u1", "user", time.Hour)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if _, err := NewManager([]byte("other")).Verify(token); err == nil {
+		t.Fatal("Verify with wrong secret: got nil error, want error")
+	}
+}
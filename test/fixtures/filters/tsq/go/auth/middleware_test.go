@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMiddleware_RejectsMissingOrInvalidToken(t *testing.T) {
+	m := NewManager([]byte("secret"))
+	called := false
+	h := m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	for _, header := range []string{"", "Bearer not-a-token", "not-bearer-at-all"} {
+		called = false
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("header %q: status = %d, want %d", header, rec.Code, http.StatusUnauthorized)
+		}
+		if called {
+			t.Errorf("header %q: next was called, want rejected before reaching it", header)
+		}
+	}
+}
+
+func TestMiddleware_PassesClaimsThrough(t *testing.T) {
+	m := NewManager([]byte("secret"))
+	token, err := m.Issue("u1", "admin", time.Hour)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	var gotClaims *Claims
+	h := m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotClaims, _ = ClaimsFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if gotClaims == nil || gotClaims.UserID != "u1" {
+		t.Fatalf("claims on context = %+v, want UserID=u1", gotClaims)
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	called := false
+	h := RequireRole("admin", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	reqWithRole := func(role string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		return req.WithContext(context.WithValue(req.Context(), claimsContextKey, &Claims{Role: role}))
+	}
+
+	called = false
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, reqWithRole("user"))
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("wrong role: status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if called {
+		t.Fatal("wrong role: next was called, want rejected")
+	}
+
+	called = false
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, reqWithRole("admin"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("matching role: status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if !called {
+		t.Fatal("matching role: next was not called")
+	}
+}
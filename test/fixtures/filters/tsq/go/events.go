@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"sync"
+)
+
+// subscriberBufferSize is how many events a slow SSE client can fall
+// behind by before it starts missing events outright.
+const subscriberBufferSize = 32
+
+// ringBufferSize is how many recent events EventBus keeps around to
+// replay to a client resuming via Last-Event-ID.
+const ringBufferSize = 256
+
+type Event struct {
+	ID   uint64      `json:"id"`
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+// EventBus keeps a ring buffer of recent events so a reconnecting SSE
+// client can resume from its Last-Event-ID instead of missing events.
+type EventBus struct {
+	mu          sync.Mutex
+	nextID      uint64
+	ring        []Event
+	subscribers map[chan Event]struct{}
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{subscribers: make(map[chan Event]struct{})}
+}
+
+// Publish is best-effort: a subscriber too far behind to accept the
+// event without blocking just misses it.
+func (b *EventBus) Publish(eventType string, data interface{}) Event {
+	if b == nil {
+		return Event{}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	ev := Event{ID: b.nextID, Type: eventType, Data: data}
+
+	b.ring = append(b.ring, ev)
+	if len(b.ring) > ringBufferSize {
+		b.ring = b.ring[len(b.ring)-ringBufferSize:]
+	}
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+	return ev
+}
+
+// Subscribe's channel is closed once ctx is done.
+func (b *EventBus) Subscribe(ctx context.Context) <-chan Event {
+	return b.subscribeFrom(ctx, 0, false)
+}
+
+// SubscribeFrom replays ring-buffered events with ID > lastEventID
+// before returning the live channel.
+func (b *EventBus) SubscribeFrom(ctx context.Context, lastEventID uint64) <-chan Event {
+	return b.subscribeFrom(ctx, lastEventID, true)
+}
+
+func (b *EventBus) subscribeFrom(ctx context.Context, lastEventID uint64, replay bool) <-chan Event {
+	b.mu.Lock()
+	// Room for the whole replay on top of the live buffer, so a resume
+	// never drops buffered events.
+	size := subscriberBufferSize
+	if replay {
+		size += len(b.ring)
+	}
+	ch := make(chan Event, size)
+	b.subscribers[ch] = struct{}{}
+	if replay {
+		for _, ev := range b.ring {
+			if ev.ID > lastEventID {
+				select {
+				case ch <- ev:
+				default:
+				}
+			}
+		}
+	}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subscribers, ch)
+		close(ch)
+		b.mu.Unlock()
+	}()
+
+	return ch
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func recvEvent(t *testing.T, ch <-chan Event) Event {
+	t.Helper()
+	select {
+	case ev := <-ch:
+		return ev
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+		return Event{}
+	}
+}
+
+func TestEventBus_PublishSubscribe(t *testing.T) {
+	bus := NewEventBus()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sub := bus.Subscribe(ctx)
+	bus.Publish("user.created", map[string]string{"id": "1"})
+
+	ev := recvEvent(t, sub)
+	if ev.ID != 1 || ev.Type != "user.created" {
+		t.Fatalf("got event %+v, want ID=1 Type=user.created", ev)
+	}
+}
+
+func TestEventBus_SubscribeFromReplaysBufferedEvents(t *testing.T) {
+	bus := NewEventBus()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus.Publish("a", nil)
+	bus.Publish("b", nil)
+	third := bus.Publish("c", nil)
+
+	sub := bus.SubscribeFrom(ctx, 1)
+
+	first := recvEvent(t, sub)
+	if first.Type != "b" {
+		t.Fatalf("first replayed event = %+v, want Type=b", first)
+	}
+	second := recvEvent(t, sub)
+	if second.ID != third.ID || second.Type != "c" {
+		t.Fatalf("second replayed event = %+v, want Type=c", second)
+	}
+}
+
+func TestEventBus_SubscribeClosesOnContextDone(t *testing.T) {
+	bus := NewEventBus()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sub := bus.Subscribe(ctx)
+	cancel()
+
+	select {
+	case _, ok := <-sub:
+		if ok {
+			t.Fatal("channel produced a value instead of closing")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for channel to close")
+	}
+}
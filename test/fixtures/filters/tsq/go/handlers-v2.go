@@ -2,17 +2,23 @@ package handlers
 
 import (
 	"encoding/json"
-	"log"
 	"net/http"
 	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/gorilla/mux"
+
+	"github.com/zetlen/makesure/handlers/auth"
 )
 
 type User struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	Role      string    `json:"role"`
-	CreatedAt time.Time `json:"created_at"`
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Email        string    `json:"email"`
+	Role         string    `json:"role"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
 }
 
 type Product struct {
@@ -23,110 +29,290 @@ type Product struct {
 }
 
 type ApiResponse struct {
-	Data    interface{} `json:"data"`
-	Status  int         `json:"status"`
-	Message string      `json:"message,omitempty"`
+	Data    interface{}            `json:"data"`
+	Status  int                    `json:"status"`
+	Message string                 `json:"message,omitempty"`
+	Meta    map[string]interface{} `json:"meta,omitempty"`
 }
 
 type UserHandler struct {
-	users  map[string]User
-	logger *log.Logger
+	repo UserRepository
+	auth *auth.Manager
+	instrumentation
 }
 
 type ProductHandler struct {
-	products map[string]Product
+	repo ProductRepository
+	instrumentation
 }
 
-func NewUserHandler(logger *log.Logger) *UserHandler {
+func NewUserHandler(repo UserRepository, authManager *auth.Manager, opts ...Option) *UserHandler {
 	return &UserHandler{
-		users:  make(map[string]User),
-		logger: logger,
+		repo:            repo,
+		auth:            authManager,
+		instrumentation: newInstrumentation(opts),
 	}
 }
 
-func NewProductHandler() *ProductHandler {
+func NewProductHandler(repo ProductRepository, opts ...Option) *ProductHandler {
 	return &ProductHandler{
-		products: make(map[string]Product),
+		repo:            repo,
+		instrumentation: newInstrumentation(opts),
 	}
 }
 
 func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
-	id := r.URL.Query().Get("id")
-	user, ok := h.users[id]
-	if !ok {
-		h.logger.Printf("User not found: %s", id)
-		sendError(w, "User not found", http.StatusNotFound)
+	id := mux.Vars(r)["id"]
+	user, err := h.repo.Find(id)
+	if err != nil {
+		h.logger.Warn("user not found", "id", id)
+		sendError(w, r, "User not found", http.StatusNotFound)
 		return
 	}
-	sendJSON(w, user)
+	sendJSON(w, r, http.StatusOK, user)
 }
 
 func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
-	var user User
-	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
-		sendError(w, "Invalid request", http.StatusBadRequest)
+	var req struct {
+		User
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, r, "Invalid request", http.StatusBadRequest)
 		return
 	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		sendError(w, r, "Could not create user", http.StatusInternalServerError)
+		return
+	}
+
+	user := req.User
+	user.PasswordHash = string(hash)
 	user.CreatedAt = time.Now()
-	h.users[user.ID] = user
-	h.logger.Printf("User created: %s", user.ID)
-	w.WriteHeader(http.StatusCreated)
-	sendJSON(w, user)
+	if err := h.repo.Create(user); err != nil {
+		sendError(w, r, "Could not create user", http.StatusInternalServerError)
+		return
+	}
+	h.logger.Info("user created", "id", user.ID)
+	h.events.Publish("user.created", user)
+	sendJSON(w, r, http.StatusCreated, user)
 }
 
+// Login verifies a user's password and, on success, returns a signed JWT
+// carrying their ID and role.
+func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID       string `json:"id"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, r, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.repo.Find(req.ID)
+	if err != nil {
+		sendError(w, r, "Invalid credentials", http.StatusUnauthorized)
+		return
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)) != nil {
+		sendError(w, r, "Invalid credentials", http.StatusUnauthorized)
+		return
+	}
+
+	token, err := h.auth.Issue(user.ID, user.Role, 0)
+	if err != nil {
+		sendError(w, r, "Could not issue token", http.StatusInternalServerError)
+		return
+	}
+	sendJSON(w, r, http.StatusOK, map[string]string{"token": token})
+}
+
+// ChangePassword updates the caller's own password hash after checking
+// their current password.
+func (h *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	claims, ok := auth.ClaimsFromContext(r.Context())
+	if !ok {
+		sendError(w, r, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		CurrentPassword string `json:"current_password"`
+		NewPassword     string `json:"new_password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, r, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.repo.Find(claims.UserID)
+	if err != nil {
+		sendError(w, r, "User not found", http.StatusNotFound)
+		return
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.CurrentPassword)) != nil {
+		sendError(w, r, "Invalid credentials", http.StatusUnauthorized)
+		return
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		sendError(w, r, "Could not change password", http.StatusInternalServerError)
+		return
+	}
+	user.PasswordHash = string(hash)
+	if err := h.repo.Update(user); err != nil {
+		sendError(w, r, "Could not change password", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UpdateUser decodes onto the existing record so fields the client
+// omits, like PasswordHash, aren't zeroed; Role is only honored from
+// an admin caller, and only the owner or an admin may update at all.
 func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
-	id := r.URL.Query().Get("id")
-	var user User
+	id := mux.Vars(r)["id"]
+
+	claims, ok := auth.ClaimsFromContext(r.Context())
+	if !ok {
+		sendError(w, r, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	isAdmin := claims.Role == "admin"
+	if claims.UserID != id && !isAdmin {
+		sendError(w, r, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	user, err := h.repo.Find(id)
+	if err != nil {
+		sendError(w, r, "User not found", http.StatusNotFound)
+		return
+	}
+	role := user.Role
 	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
-		sendError(w, "Invalid request", http.StatusBadRequest)
+		sendError(w, r, "Invalid request", http.StatusBadRequest)
 		return
 	}
 	user.ID = id
-	h.users[id] = user
-	sendJSON(w, user)
+	if !isAdmin {
+		user.Role = role
+	}
+
+	if err := h.repo.Update(user); err != nil {
+		if err == ErrNotFound {
+			sendError(w, r, "User not found", http.StatusNotFound)
+			return
+		}
+		sendError(w, r, "Could not update user", http.StatusInternalServerError)
+		return
+	}
+	h.events.Publish("user.updated", user)
+	sendJSON(w, r, http.StatusOK, user)
 }
 
 func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
-	id := r.URL.Query().Get("id")
-	delete(h.users, id)
+	id := mux.Vars(r)["id"]
+	if err := h.repo.Delete(id); err != nil {
+		if err == ErrNotFound {
+			sendError(w, r, "User not found", http.StatusNotFound)
+			return
+		}
+		sendError(w, r, "Could not delete user", http.StatusInternalServerError)
+		return
+	}
+	h.events.Publish("user.deleted", map[string]string{"id": id})
 	w.WriteHeader(http.StatusNoContent)
 }
 
 func (h *ProductHandler) GetProduct(w http.ResponseWriter, r *http.Request) {
-	id := r.URL.Query().Get("id")
-	product, ok := h.products[id]
-	if !ok {
-		sendError(w, "Product not found", http.StatusNotFound)
+	id := mux.Vars(r)["id"]
+	product, err := h.repo.Find(id)
+	if err != nil {
+		sendError(w, r, "Product not found", http.StatusNotFound)
 		return
 	}
-	sendJSON(w, product)
+	sendJSON(w, r, http.StatusOK, product)
 }
 
-func (h *ProductHandler) ListProducts(w http.ResponseWriter, r *http.Request) {
-	products := make([]Product, 0, len(h.products))
-	for _, p := range h.products {
-		products = append(products, p)
+func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
+	var product Product
+	if err := json.NewDecoder(r.Body).Decode(&product); err != nil {
+		sendError(w, r, "Invalid request", http.StatusBadRequest)
+		return
+	}
+	if err := h.repo.Create(product); err != nil {
+		sendError(w, r, "Could not create product", http.StatusInternalServerError)
+		return
+	}
+	h.events.Publish("product.created", product)
+	sendJSON(w, r, http.StatusCreated, product)
+}
+
+func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	var product Product
+	if err := json.NewDecoder(r.Body).Decode(&product); err != nil {
+		sendError(w, r, "Invalid request", http.StatusBadRequest)
+		return
+	}
+	product.ID = id
+	if err := h.repo.Update(product); err != nil {
+		if err == ErrNotFound {
+			sendError(w, r, "Product not found", http.StatusNotFound)
+			return
+		}
+		sendError(w, r, "Could not update product", http.StatusInternalServerError)
+		return
 	}
-	sendJSON(w, products)
+	h.events.Publish("product.updated", product)
+	sendJSON(w, r, http.StatusOK, product)
 }
 
-func sendJSON(w http.ResponseWriter, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(ApiResponse{
-		Data:   data,
-		Status: http.StatusOK,
-	})
+func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if err := h.repo.Delete(id); err != nil {
+		if err == ErrNotFound {
+			sendError(w, r, "Product not found", http.StatusNotFound)
+			return
+		}
+		sendError(w, r, "Could not delete product", http.StatusInternalServerError)
+		return
+	}
+	h.events.Publish("product.deleted", map[string]string{"id": id})
+	w.WriteHeader(http.StatusNoContent)
 }
 
-func sendError(w http.ResponseWriter, message string, status int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(ApiResponse{
-		Status:  status,
-		Message: message,
-	})
+func (h *ProductHandler) ListProducts(w http.ResponseWriter, r *http.Request) {
+	products, err := h.repo.All()
+	if err != nil {
+		sendError(w, r, "Could not list products", http.StatusInternalServerError)
+		return
+	}
+
+	params := parseListParams(r)
+	products = filterProducts(products, params)
+	sortProducts(products, params.sort)
+	total := len(products)
+	start, end := params.bounds(total)
+	sendList(w, r, products[start:end], total, params)
 }
 
-func formatResponse(data interface{}) ([]byte, error) {
-	return json.Marshal(ApiResponse{Data: data, Status: http.StatusOK})
+func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := h.repo.All()
+	if err != nil {
+		sendError(w, r, "Could not list users", http.StatusInternalServerError)
+		return
+	}
+
+	params := parseListParams(r)
+	users = filterUsers(users, params)
+	sortUsers(users, params.sort)
+	total := len(users)
+	start, end := params.bounds(total)
+	sendList(w, r, users[start:end], total, params)
 }
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics is shared by every instrumented handler; register it once
+// and pass it to each handler constructor via WithMetrics.
+type Metrics struct {
+	registry *prometheus.Registry
+	requests *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+}
+
+// NewMetrics builds the request-count and latency-histogram collectors
+// and registers them with reg. reg is also what Handler serves, so a
+// caller using its own prometheus.NewRegistry() — the usual reason to
+// pass one in at all, e.g. in tests or a second instance — sees its
+// collectors at /metrics instead of the global DefaultGatherer.
+func NewMetrics(reg *prometheus.Registry) *Metrics {
+	m := &Metrics{
+		registry: reg,
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "makesure_http_requests_total",
+			Help: "Total HTTP requests handled, by handler, method and status code.",
+		}, []string{"handler", "method", "status"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "makesure_http_request_duration_seconds",
+			Help:    "HTTP request latency in seconds, by handler and method.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"handler", "method"}),
+	}
+	reg.MustRegister(m.requests, m.latency)
+	return m
+}
+
+func (m *Metrics) observe(handlerName, method string, status int, dur time.Duration) {
+	if m == nil {
+		return
+	}
+	m.requests.WithLabelValues(handlerName, method, strconv.Itoa(status)).Inc()
+	m.latency.WithLabelValues(handlerName, method).Observe(dur.Seconds())
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestMetrics_HandlerServesOwnRegistry(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewMetrics(reg)
+	m.observe("get_user", "GET", 200, 5*time.Millisecond)
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, req)
+
+	body := rec.Body.String()
+	if !strings.Contains(body, "makesure_http_requests_total") {
+		t.Fatalf("/metrics body missing makesure_http_requests_total; got:\n%s", body)
+	}
+}
+
+func TestMetrics_ObserveNilReceiverIsNoop(t *testing.T) {
+	var m *Metrics
+	m.observe("get_user", "GET", 200, time.Millisecond) // must not panic
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/zetlen/makesure/handlers/auth"
+)
+
+// instrumentation is embedded into UserHandler and ProductHandler so
+// WithLogger/WithMetrics/WithEvents can wire in cross-cutting behavior
+// without changing every constructor call site that doesn't care about
+// any of it.
+type instrumentation struct {
+	logger  *slog.Logger
+	metrics *Metrics
+	events  *EventBus
+}
+
+type Option func(*instrumentation)
+
+func WithLogger(logger *slog.Logger) Option {
+	return func(i *instrumentation) { i.logger = logger }
+}
+
+func WithMetrics(m *Metrics) Option {
+	return func(i *instrumentation) { i.metrics = m }
+}
+
+func WithEvents(bus *EventBus) Option {
+	return func(i *instrumentation) { i.events = bus }
+}
+
+func newInstrumentation(opts []Option) instrumentation {
+	inst := instrumentation{logger: slog.Default()}
+	for _, opt := range opts {
+		opt(&inst)
+	}
+	return inst
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	n, err := s.ResponseWriter.Write(b)
+	s.bytes += n
+	return n, err
+}
+
+func Instrument(handlerName string, metrics *Metrics, logger *slog.Logger, next http.HandlerFunc) http.HandlerFunc {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next(rec, r)
+
+		dur := time.Since(start)
+		metrics.observe(handlerName, r.Method, rec.status, dur)
+
+		attrs := []any{
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"duration_ms", dur.Milliseconds(),
+			"bytes", rec.bytes,
+			"remote_addr", r.RemoteAddr,
+		}
+		if claims, ok := auth.ClaimsFromContext(r.Context()); ok {
+			attrs = append(attrs, "user_id", claims.UserID)
+		}
+		logger.Info("request", attrs...)
+	}
+}
@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// sortKey is one comma-separated term of a ?sort= parameter, e.g. the
+// "-price" in "?sort=-price,name".
+type sortKey struct {
+	field string
+	desc  bool
+}
+
+type listParams struct {
+	page     int
+	pageSize int
+	filters  map[string]string
+	sort     []sortKey
+	q        string
+}
+
+// parseListParams reads page[number], page[size], filter[<field>]=,
+// sort=, and q= off of r, clamping the page size to maxPageSize so a
+// client can't force an unbounded scan.
+func parseListParams(r *http.Request) listParams {
+	query := r.URL.Query()
+
+	page := atoiDefault(query.Get("page[number]"), 1)
+	if page < 1 {
+		page = 1
+	}
+	pageSize := atoiDefault(query.Get("page[size]"), defaultPageSize)
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	filters := make(map[string]string)
+	for key, values := range query {
+		if strings.HasPrefix(key, "filter[") && strings.HasSuffix(key, "]") && len(values) > 0 {
+			field := strings.TrimSuffix(strings.TrimPrefix(key, "filter["), "]")
+			filters[field] = values[0]
+		}
+	}
+
+	var sorts []sortKey
+	if raw := query.Get("sort"); raw != "" {
+		for _, term := range strings.Split(raw, ",") {
+			term = strings.TrimSpace(term)
+			if term == "" {
+				continue
+			}
+			desc := strings.HasPrefix(term, "-")
+			sorts = append(sorts, sortKey{field: strings.TrimPrefix(term, "-"), desc: desc})
+		}
+	}
+
+	return listParams{
+		page:     page,
+		pageSize: pageSize,
+		filters:  filters,
+		sort:     sorts,
+		q:        query.Get("q"),
+	}
+}
+
+func atoiDefault(s string, def int) int {
+	if s == "" {
+		return def
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+func (p listParams) bounds(total int) (start, end int) {
+	start = (p.page - 1) * p.pageSize
+	if start > total {
+		start = total
+	}
+	end = start + p.pageSize
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+func (p listParams) lastPage(total int) int {
+	if p.pageSize == 0 {
+		return 1
+	}
+	pages := (total + p.pageSize - 1) / p.pageSize
+	if pages < 1 {
+		pages = 1
+	}
+	return pages
+}
+
+// paginationLinks reuses the request's own query string so filters
+// and sorting survive paging.
+func paginationLinks(r *http.Request, p listParams, total int) *jsonAPILinks {
+	links := &jsonAPILinks{Self: r.URL.RequestURI()}
+	if p.page > 1 {
+		links.Prev = linkWithPage(r.URL, p.page-1)
+	}
+	if p.page < p.lastPage(total) {
+		links.Next = linkWithPage(r.URL, p.page+1)
+	}
+	return links
+}
+
+func linkWithPage(u *url.URL, page int) string {
+	copied := *u
+	q := copied.Query()
+	q.Set("page[number]", strconv.Itoa(page))
+	copied.RawQuery = q.Encode()
+	return copied.Path + "?" + copied.RawQuery
+}
+
+func sendList(w http.ResponseWriter, r *http.Request, data interface{}, total int, p listParams) {
+	meta := map[string]interface{}{
+		"total": total,
+		"page":  p.page,
+	}
+
+	if wantsJSONAPI(r) {
+		if doc, ok := toJSONAPIData(data, r); ok {
+			doc.Meta = meta
+			doc.Links = paginationLinks(r, p, total)
+			writeJSONAPIDoc(w, http.StatusOK, doc)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ApiResponse{
+		Data:   data,
+		Status: http.StatusOK,
+		Meta:   meta,
+	})
+}
+
+func filterProducts(products []Product, p listParams) []Product {
+	out := make([]Product, 0, len(products))
+	for _, product := range products {
+		if category, ok := p.filters["category"]; ok && product.Category != category {
+			continue
+		}
+		if p.q != "" && !strings.Contains(strings.ToLower(product.Name), strings.ToLower(p.q)) {
+			continue
+		}
+		out = append(out, product)
+	}
+	return out
+}
+
+func sortProducts(products []Product, keys []sortKey) {
+	sort.SliceStable(products, func(i, j int) bool {
+		for _, key := range keys {
+			a, b := products[i], products[j]
+			switch key.field {
+			case "price":
+				if a.Price == b.Price {
+					continue
+				}
+				return (a.Price < b.Price) != key.desc
+			case "name":
+				if a.Name == b.Name {
+					continue
+				}
+				return (a.Name < b.Name) != key.desc
+			case "category":
+				if a.Category == b.Category {
+					continue
+				}
+				return (a.Category < b.Category) != key.desc
+			}
+		}
+		return false
+	})
+}
+
+func filterUsers(users []User, p listParams) []User {
+	out := make([]User, 0, len(users))
+	for _, user := range users {
+		if role, ok := p.filters["role"]; ok && user.Role != role {
+			continue
+		}
+		if p.q != "" {
+			q := strings.ToLower(p.q)
+			if !strings.Contains(strings.ToLower(user.Name), q) && !strings.Contains(strings.ToLower(user.Email), q) {
+				continue
+			}
+		}
+		out = append(out, user)
+	}
+	return out
+}
+
+func sortUsers(users []User, keys []sortKey) {
+	sort.SliceStable(users, func(i, j int) bool {
+		for _, key := range keys {
+			a, b := users[i], users[j]
+			switch key.field {
+			case "name":
+				if a.Name == b.Name {
+					continue
+				}
+				return (a.Name < b.Name) != key.desc
+			case "email":
+				if a.Email == b.Email {
+					continue
+				}
+				return (a.Email < b.Email) != key.desc
+			case "role":
+				if a.Role == b.Role {
+					continue
+				}
+				return (a.Role < b.Role) != key.desc
+			case "created_at":
+				if a.CreatedAt.Equal(b.CreatedAt) {
+					continue
+				}
+				return a.CreatedAt.Before(b.CreatedAt) != key.desc
+			}
+		}
+		return false
+	})
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListParams_Bounds(t *testing.T) {
+	cases := []struct {
+		page, pageSize, total int
+		wantStart, wantEnd    int
+	}{
+		{page: 1, pageSize: 10, total: 25, wantStart: 0, wantEnd: 10},
+		{page: 3, pageSize: 10, total: 25, wantStart: 20, wantEnd: 25},
+		{page: 5, pageSize: 10, total: 25, wantStart: 25, wantEnd: 25},
+	}
+	for _, c := range cases {
+		p := listParams{page: c.page, pageSize: c.pageSize}
+		start, end := p.bounds(c.total)
+		if start != c.wantStart || end != c.wantEnd {
+			t.Errorf("bounds(page=%d, pageSize=%d, total=%d) = (%d, %d), want (%d, %d)",
+				c.page, c.pageSize, c.total, start, end, c.wantStart, c.wantEnd)
+		}
+	}
+}
+
+func TestParseListParams_ClampsPageSize(t *testing.T) {
+	req := httptest.NewRequest("GET", "/users?page[size]=1000", nil)
+	p := parseListParams(req)
+	if p.pageSize != maxPageSize {
+		t.Fatalf("pageSize = %d, want clamped to %d", p.pageSize, maxPageSize)
+	}
+}
+
+func TestParseListParams_DefaultsAndSort(t *testing.T) {
+	req := httptest.NewRequest("GET", "/users?sort=-name,email", nil)
+	p := parseListParams(req)
+
+	if p.page != 1 || p.pageSize != defaultPageSize {
+		t.Fatalf("page=%d pageSize=%d, want 1/%d", p.page, p.pageSize, defaultPageSize)
+	}
+	if len(p.sort) != 2 || p.sort[0] != (sortKey{field: "name", desc: true}) || p.sort[1] != (sortKey{field: "email", desc: false}) {
+		t.Fatalf("sort = %+v, want [{name true} {email false}]", p.sort)
+	}
+}
+
+func TestSortUsers_MultiKey(t *testing.T) {
+	users := []User{
+		{ID: "1", Name: "Bob", Role: "user"},
+		{ID: "2", Name: "Ada", Role: "admin"},
+		{ID: "3", Name: "Ada", Role: "user"},
+	}
+	sortUsers(users, []sortKey{{field: "name"}, {field: "role"}})
+
+	want := []string{"2", "3", "1"}
+	for i, id := range want {
+		if users[i].ID != id {
+			t.Fatalf("sortUsers order = %v, want ids in order %v", users, want)
+		}
+	}
+}
+
+func TestSortProducts_DescPrice(t *testing.T) {
+	products := []Product{
+		{ID: "1", Price: 5},
+		{ID: "2", Price: 20},
+		{ID: "3", Price: 10},
+	}
+	sortProducts(products, []sortKey{{field: "price", desc: true}})
+
+	want := []string{"2", "3", "1"}
+	for i, id := range want {
+		if products[i].ID != id {
+			t.Fatalf("sortProducts order = %v, want ids in order %v", products, want)
+		}
+	}
+}
+
+func TestFilterUsers_RoleAndQuery(t *testing.T) {
+	users := []User{
+		{ID: "1", Name: "Ada Lovelace", Email: "ada@example.com", Role: "admin"},
+		{ID: "2", Name: "Bob", Email: "bob@example.com", Role: "user"},
+	}
+
+	got := filterUsers(users, listParams{filters: map[string]string{"role": "admin"}})
+	if len(got) != 1 || got[0].ID != "1" {
+		t.Fatalf("filterUsers by role = %+v, want only user 1", got)
+	}
+
+	got = filterUsers(users, listParams{q: "bob"})
+	if len(got) != 1 || got[0].ID != "2" {
+		t.Fatalf("filterUsers by q = %+v, want only user 2", got)
+	}
+}
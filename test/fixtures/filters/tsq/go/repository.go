@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrNotFound is returned by Repository implementations when the
+// requested entity does not exist.
+var ErrNotFound = errors.New("not found")
+
+type UserRepository interface {
+	Find(id string) (User, error)
+	All() ([]User, error)
+	Create(user User) error
+	Update(user User) error
+	Delete(id string) error
+}
+
+type ProductRepository interface {
+	Find(id string) (Product, error)
+	All() ([]Product, error)
+	Create(product Product) error
+	Update(product Product) error
+	Delete(id string) error
+}
+
+type InMemoryUserRepository struct {
+	mu    sync.RWMutex
+	users map[string]User
+}
+
+func NewInMemoryUserRepository() *InMemoryUserRepository {
+	return &InMemoryUserRepository{
+		users: make(map[string]User),
+	}
+}
+
+func (r *InMemoryUserRepository) Find(id string) (User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	user, ok := r.users[id]
+	if !ok {
+		return User{}, ErrNotFound
+	}
+	return user, nil
+}
+
+func (r *InMemoryUserRepository) All() ([]User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	users := make([]User, 0, len(r.users))
+	for _, u := range r.users {
+		users = append(users, u)
+	}
+	return users, nil
+}
+
+func (r *InMemoryUserRepository) Create(user User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *InMemoryUserRepository) Update(user User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[user.ID]; !ok {
+		return ErrNotFound
+	}
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *InMemoryUserRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.users, id)
+	return nil
+}
+
+type InMemoryProductRepository struct {
+	mu       sync.RWMutex
+	products map[string]Product
+}
+
+func NewInMemoryProductRepository() *InMemoryProductRepository {
+	return &InMemoryProductRepository{
+		products: make(map[string]Product),
+	}
+}
+
+func (r *InMemoryProductRepository) Find(id string) (Product, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	product, ok := r.products[id]
+	if !ok {
+		return Product{}, ErrNotFound
+	}
+	return product, nil
+}
+
+func (r *InMemoryProductRepository) All() ([]Product, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	products := make([]Product, 0, len(r.products))
+	for _, p := range r.products {
+		products = append(products, p)
+	}
+	return products, nil
+}
+
+func (r *InMemoryProductRepository) Create(product Product) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.products[product.ID] = product
+	return nil
+}
+
+func (r *InMemoryProductRepository) Update(product Product) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.products[product.ID]; !ok {
+		return ErrNotFound
+	}
+	r.products[product.ID] = product
+	return nil
+}
+
+func (r *InMemoryProductRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.products[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.products, id)
+	return nil
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// MongoUserRepository is a UserRepository backed by a MongoDB collection.
+// It holds a single long-lived session and copies it per call, closing
+// the copy when the call returns.
+type MongoUserRepository struct {
+	session *mgo.Session
+	coll    string
+	db      string
+}
+
+// NewMongoUserRepository's caller owns session's lifecycle.
+func NewMongoUserRepository(session *mgo.Session, db, coll string) *MongoUserRepository {
+	return &MongoUserRepository{session: session, db: db, coll: coll}
+}
+
+func (r *MongoUserRepository) collection() (*mgo.Collection, func()) {
+	s := r.session.Copy()
+	return s.DB(r.db).C(r.coll), s.Close
+}
+
+func (r *MongoUserRepository) Find(id string) (User, error) {
+	c, closeSession := r.collection()
+	defer closeSession()
+
+	var user User
+	if err := c.FindId(id).One(&user); err != nil {
+		if err == mgo.ErrNotFound {
+			return User{}, ErrNotFound
+		}
+		return User{}, err
+	}
+	return user, nil
+}
+
+func (r *MongoUserRepository) All() ([]User, error) {
+	c, closeSession := r.collection()
+	defer closeSession()
+
+	var users []User
+	if err := c.Find(bson.M{}).All(&users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *MongoUserRepository) Create(user User) error {
+	c, closeSession := r.collection()
+	defer closeSession()
+	return c.Insert(user)
+}
+
+func (r *MongoUserRepository) Update(user User) error {
+	c, closeSession := r.collection()
+	defer closeSession()
+
+	if err := c.UpdateId(user.ID, user); err != nil {
+		if err == mgo.ErrNotFound {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *MongoUserRepository) Delete(id string) error {
+	c, closeSession := r.collection()
+	defer closeSession()
+
+	if err := c.RemoveId(id); err != nil {
+		if err == mgo.ErrNotFound {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// MongoProductRepository follows the same session-copy-per-call
+// pattern as MongoUserRepository.
+type MongoProductRepository struct {
+	session *mgo.Session
+	coll    string
+	db      string
+}
+
+// NewMongoProductRepository's caller owns session's lifecycle.
+func NewMongoProductRepository(session *mgo.Session, db, coll string) *MongoProductRepository {
+	return &MongoProductRepository{session: session, db: db, coll: coll}
+}
+
+func (r *MongoProductRepository) collection() (*mgo.Collection, func()) {
+	s := r.session.Copy()
+	return s.DB(r.db).C(r.coll), s.Close
+}
+
+func (r *MongoProductRepository) Find(id string) (Product, error) {
+	c, closeSession := r.collection()
+	defer closeSession()
+
+	var product Product
+	if err := c.FindId(id).One(&product); err != nil {
+		if err == mgo.ErrNotFound {
+			return Product{}, ErrNotFound
+		}
+		return Product{}, err
+	}
+	return product, nil
+}
+
+func (r *MongoProductRepository) All() ([]Product, error) {
+	c, closeSession := r.collection()
+	defer closeSession()
+
+	var products []Product
+	if err := c.Find(bson.M{}).All(&products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (r *MongoProductRepository) Create(product Product) error {
+	c, closeSession := r.collection()
+	defer closeSession()
+	return c.Insert(product)
+}
+
+func (r *MongoProductRepository) Update(product Product) error {
+	c, closeSession := r.collection()
+	defer closeSession()
+
+	if err := c.UpdateId(product.ID, product); err != nil {
+		if err == mgo.ErrNotFound {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *MongoProductRepository) Delete(id string) error {
+	c, closeSession := r.collection()
+	defer closeSession()
+
+	if err := c.RemoveId(id); err != nil {
+		if err == mgo.ErrNotFound {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
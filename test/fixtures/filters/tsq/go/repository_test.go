@@ -0,0 +1,79 @@
+package handlers
+
+import "testing"
+
+func TestInMemoryUserRepository_CRUD(t *testing.T) {
+	repo := NewInMemoryUserRepository()
+
+	if _, err := repo.Find("1"); err != ErrNotFound {
+		t.Fatalf("Find on empty repo: got err %v, want ErrNotFound", err)
+	}
+
+	user := User{ID: "1", Name: "Ada"}
+	if err := repo.Create(user); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	got, err := repo.Find("1")
+	if err != nil {
+		t.Fatalf("Find after Create: %v", err)
+	}
+	if got != user {
+		t.Fatalf("Find after Create = %+v, want %+v", got, user)
+	}
+
+	user.Name = "Ada Lovelace"
+	if err := repo.Update(user); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	got, _ = repo.Find("1")
+	if got.Name != "Ada Lovelace" {
+		t.Fatalf("Find after Update: got Name %q, want %q", got.Name, "Ada Lovelace")
+	}
+
+	if err := repo.Update(User{ID: "missing"}); err != ErrNotFound {
+		t.Fatalf("Update on missing id: got err %v, want ErrNotFound", err)
+	}
+
+	all, err := repo.All()
+	if err != nil {
+		t.Fatalf("All: %v", err)
+	}
+	if len(all) != 1 {
+		t.Fatalf("All: got %d users, want 1", len(all))
+	}
+
+	if err := repo.Delete("1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := repo.Find("1"); err != ErrNotFound {
+		t.Fatalf("Find after Delete: got err %v, want ErrNotFound", err)
+	}
+	if err := repo.Delete("1"); err != ErrNotFound {
+		t.Fatalf("Delete on missing id: got err %v, want ErrNotFound", err)
+	}
+}
+
+func TestInMemoryProductRepository_CRUD(t *testing.T) {
+	repo := NewInMemoryProductRepository()
+
+	product := Product{ID: "p1", Name: "Widget", Price: 9.99}
+	if err := repo.Create(product); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	got, err := repo.Find("p1")
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if got != product {
+		t.Fatalf("Find = %+v, want %+v", got, product)
+	}
+
+	if err := repo.Delete("p1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := repo.Find("p1"); err != ErrNotFound {
+		t.Fatalf("Find after Delete: got err %v, want ErrNotFound", err)
+	}
+}
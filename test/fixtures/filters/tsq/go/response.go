@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const jsonAPIMediaType = "application/vnd.api+json"
+
+// identifiable resources render as a JSON:API resource object: their
+// own JSON struct tags double as the attribute mapping.
+type identifiable interface {
+	ResourceType() string
+	ResourceID() string
+}
+
+type jsonAPIResource struct {
+	Type       string                 `json:"type"`
+	ID         string                 `json:"id"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+type jsonAPIError struct {
+	Status string `json:"status"`
+	Title  string `json:"title"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type jsonAPILinks struct {
+	Self string `json:"self,omitempty"`
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+type jsonAPIDocument struct {
+	Data   interface{}            `json:"data,omitempty"`
+	Errors []jsonAPIError         `json:"errors,omitempty"`
+	Meta   map[string]interface{} `json:"meta,omitempty"`
+	Links  *jsonAPILinks          `json:"links,omitempty"`
+}
+
+func wantsJSONAPI(r *http.Request) bool {
+	return r.Header.Get("Accept") == jsonAPIMediaType
+}
+
+// toResource derives attributes from v's own JSON encoding; fields
+// trims them down when the request used ?fields[type]=.
+func toResource(v identifiable, fields map[string]bool) (*jsonAPIResource, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	attrs := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &attrs); err != nil {
+		return nil, err
+	}
+	delete(attrs, "id")
+	if fields != nil {
+		for k := range attrs {
+			if !fields[k] {
+				delete(attrs, k)
+			}
+		}
+	}
+	return &jsonAPIResource{Type: v.ResourceType(), ID: v.ResourceID(), Attributes: attrs}, nil
+}
+
+// requestedFields accepts both the plural resource type and its
+// singular form, e.g. fields[users] or fields[user].
+func requestedFields(r *http.Request, resourceType string) map[string]bool {
+	q := r.URL.Query()
+	raw := q.Get("fields[" + resourceType + "]")
+	if raw == "" {
+		raw = q.Get("fields[" + strings.TrimSuffix(resourceType, "s") + "]")
+	}
+	if raw == "" {
+		return nil
+	}
+	set := make(map[string]bool)
+	for _, f := range strings.Split(raw, ",") {
+		set[strings.TrimSpace(f)] = true
+	}
+	return set
+}
+
+// requestedIncludes is plumbed through for forward-compatibility; no
+// resource type declares relationships yet, so it's unused today.
+func requestedIncludes(r *http.Request) []string {
+	raw := r.URL.Query().Get("include")
+	if raw == "" {
+		return nil
+	}
+	return strings.Split(raw, ",")
+}
+
+func selfLink(r *http.Request) *jsonAPILinks {
+	return &jsonAPILinks{Self: r.URL.RequestURI()}
+}
+
+// toJSONAPIData returns ok false for anything but a single resource or
+// slice of a declared resource type, so the caller can fall back to
+// the plain envelope.
+func toJSONAPIData(data interface{}, r *http.Request) (*jsonAPIDocument, bool) {
+	requestedIncludes(r) // parsed for forward-compatibility; nothing to include yet
+
+	switch v := data.(type) {
+	case identifiable:
+		res, err := toResource(v, requestedFields(r, v.ResourceType()))
+		if err != nil {
+			return nil, false
+		}
+		return &jsonAPIDocument{Data: res, Links: selfLink(r)}, true
+	case []User:
+		resources := make([]*jsonAPIResource, 0, len(v))
+		var fields map[string]bool
+		for i, u := range v {
+			if i == 0 {
+				fields = requestedFields(r, u.ResourceType())
+			}
+			res, err := toResource(u, fields)
+			if err != nil {
+				return nil, false
+			}
+			resources = append(resources, res)
+		}
+		return &jsonAPIDocument{
+			Data:  resources,
+			Meta:  map[string]interface{}{"total": len(v)},
+			Links: selfLink(r),
+		}, true
+	case []Product:
+		resources := make([]*jsonAPIResource, 0, len(v))
+		var fields map[string]bool
+		for i, p := range v {
+			if i == 0 {
+				fields = requestedFields(r, p.ResourceType())
+			}
+			res, err := toResource(p, fields)
+			if err != nil {
+				return nil, false
+			}
+			resources = append(resources, res)
+		}
+		return &jsonAPIDocument{
+			Data:  resources,
+			Meta:  map[string]interface{}{"total": len(v)},
+			Links: selfLink(r),
+		}, true
+	default:
+		return nil, false
+	}
+}
+
+func writeJSONAPIDoc(w http.ResponseWriter, status int, doc *jsonAPIDocument) {
+	w.Header().Set("Content-Type", jsonAPIMediaType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(doc)
+}
+
+// sendJSON owns the status line; callers must not call w.WriteHeader
+// themselves before calling it.
+func sendJSON(w http.ResponseWriter, r *http.Request, status int, data interface{}) {
+	if wantsJSONAPI(r) {
+		if doc, ok := toJSONAPIData(data, r); ok {
+			writeJSONAPIDoc(w, status, doc)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(ApiResponse{
+		Data:   data,
+		Status: status,
+	})
+}
+
+func sendError(w http.ResponseWriter, r *http.Request, message string, status int) {
+	if wantsJSONAPI(r) {
+		writeJSONAPIDoc(w, status, &jsonAPIDocument{
+			Errors: []jsonAPIError{{
+				Status: strconv.Itoa(status),
+				Title:  http.StatusText(status),
+				Detail: message,
+			}},
+		})
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(ApiResponse{
+		Status:  status,
+		Message: message,
+	})
+}
+
+func formatResponse(data interface{}) ([]byte, error) {
+	return json.Marshal(ApiResponse{Data: data, Status: http.StatusOK})
+}
+
+func (u User) ResourceType() string    { return "users" }
+func (u User) ResourceID() string      { return u.ID }
+func (p Product) ResourceType() string { return "products" }
+func (p Product) ResourceID() string   { return p.ID }
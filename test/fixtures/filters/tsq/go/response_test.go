@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSendJSON_StatusForwardedToBothFormats(t *testing.T) {
+	user := User{ID: "1", Name: "Ada"}
+
+	for _, accept := range []string{"", jsonAPIMediaType} {
+		req := httptest.NewRequest(http.MethodPost, "/users", nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+
+		sendJSON(rec, req, http.StatusCreated, user)
+
+		if rec.Code != http.StatusCreated {
+			t.Errorf("Accept=%q: status = %d, want %d", accept, rec.Code, http.StatusCreated)
+		}
+	}
+}
+
+func TestSendJSON_PlainEnvelopeForNonIdentifiable(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", jsonAPIMediaType)
+	rec := httptest.NewRecorder()
+
+	sendJSON(rec, req, http.StatusOK, map[string]string{"token": "abc"})
+
+	var body ApiResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.Status != http.StatusOK {
+		t.Fatalf("body.Status = %d, want %d", body.Status, http.StatusOK)
+	}
+}
+
+func TestSendJSON_JSONAPIDocForIdentifiable(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set("Accept", jsonAPIMediaType)
+	rec := httptest.NewRecorder()
+
+	sendJSON(rec, req, http.StatusOK, User{ID: "1", Name: "Ada"})
+
+	if ct := rec.Header().Get("Content-Type"); ct != jsonAPIMediaType {
+		t.Fatalf("Content-Type = %q, want %q", ct, jsonAPIMediaType)
+	}
+
+	var doc jsonAPIDocument
+	if err := json.NewDecoder(rec.Body).Decode(&doc); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	res, ok := doc.Data.(map[string]interface{})
+	if !ok {
+		t.Fatalf("doc.Data = %#v, want a resource object", doc.Data)
+	}
+	if res["type"] != "users" || res["id"] != "1" {
+		t.Fatalf("resource = %+v, want type=users id=1", res)
+	}
+}
+
+func TestRequestedFields_AcceptsSingularAndPlural(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/users?fields[user]=name", nil)
+	fields := requestedFields(req, "users")
+	if !fields["name"] {
+		t.Fatalf("fields = %+v, want name=true via singular key", fields)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/users?fields[users]=email", nil)
+	fields = requestedFields(req, "users")
+	if !fields["email"] {
+		t.Fatalf("fields = %+v, want email=true via plural key", fields)
+	}
+}
+
+func TestToResource_TrimsToRequestedFields(t *testing.T) {
+	res, err := toResource(User{ID: "1", Name: "Ada", Email: "ada@example.com"}, map[string]bool{"name": true})
+	if err != nil {
+		t.Fatalf("toResource: %v", err)
+	}
+	if _, ok := res.Attributes["email"]; ok {
+		t.Fatalf("attributes = %+v, want email excluded", res.Attributes)
+	}
+	if _, ok := res.Attributes["name"]; !ok {
+		t.Fatalf("attributes = %+v, want name included", res.Attributes)
+	}
+}
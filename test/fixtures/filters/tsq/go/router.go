@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/zetlen/makesure/handlers/auth"
+)
+
+func NewRouter(users *UserHandler, products *ProductHandler, authManager *auth.Manager) *mux.Router {
+	r := mux.NewRouter()
+
+	userRoute := func(name string, h http.HandlerFunc) http.HandlerFunc {
+		return Instrument(name, users.metrics, users.logger, h)
+	}
+	productRoute := func(name string, h http.HandlerFunc) http.HandlerFunc {
+		return Instrument(name, products.metrics, products.logger, h)
+	}
+	authed := func(h http.HandlerFunc) http.Handler {
+		return authManager.Middleware(h)
+	}
+	admin := func(h http.HandlerFunc) http.Handler {
+		return authManager.Middleware(auth.RequireRole("admin", h))
+	}
+
+	r.HandleFunc("/login", userRoute("login", users.Login)).Methods(http.MethodPost)
+
+	r.Handle("/users", authed(userRoute("list_users", users.ListUsers))).Methods(http.MethodGet)
+	r.Handle("/users/{id}", authed(userRoute("get_user", users.GetUser))).Methods(http.MethodGet)
+	r.Handle("/users", admin(userRoute("create_user", users.CreateUser))).Methods(http.MethodPost)
+	r.Handle("/users/{id}", authed(userRoute("update_user", users.UpdateUser))).Methods(http.MethodPut)
+	r.Handle("/users/{id}", admin(userRoute("delete_user", users.DeleteUser))).Methods(http.MethodDelete)
+	r.Handle("/users/password", authed(userRoute("change_password", users.ChangePassword))).Methods(http.MethodPost)
+
+	r.HandleFunc("/products", productRoute("list_products", products.ListProducts)).Methods(http.MethodGet)
+	r.HandleFunc("/products/{id}", productRoute("get_product", products.GetProduct)).Methods(http.MethodGet)
+	r.Handle("/products", authed(productRoute("create_product", products.CreateProduct))).Methods(http.MethodPost)
+	r.Handle("/products/{id}", authed(productRoute("update_product", products.UpdateProduct))).Methods(http.MethodPut)
+	r.Handle("/products/{id}", admin(productRoute("delete_product", products.DeleteProduct))).Methods(http.MethodDelete)
+
+	metrics := users.metrics
+	if metrics == nil {
+		metrics = products.metrics
+	}
+	if metrics != nil {
+		r.Handle("/metrics", metrics.Handler()).Methods(http.MethodGet)
+	}
+
+	bus := users.events
+	if bus == nil {
+		bus = products.events
+	}
+	if bus != nil {
+		r.HandleFunc("/events", ServeEvents(bus)).Methods(http.MethodGet)
+	}
+
+	return r
+}